@@ -0,0 +1,42 @@
+package log
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx that carries logger, retrievable later
+// with FromContext. Controller reconciliation loops can thread ctx through
+// without passing logger as a separate argument at every call site.
+func NewContext(ctx context.Context, logger logr.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext returns the logr.Logger stored in ctx by NewContext, or a
+// discard logger if ctx carries none.
+func FromContext(ctx context.Context) logr.Logger {
+	if logger, ok := ctx.Value(ctxKey{}).(logr.Logger); ok {
+		return logger
+	}
+	return logr.Discard()
+}
+
+// contextKeysAndValues extracts the active OpenTelemetry span, if any, from
+// s.ctx and returns it as trace_id/span_id key/value pairs so lines emitted
+// through this sink are automatically correlated with distributed traces.
+func (s *sink) contextKeysAndValues() []interface{} {
+	if s.ctx == nil {
+		return nil
+	}
+
+	spanCtx := trace.SpanContextFromContext(s.ctx)
+	if !spanCtx.IsValid() {
+		return nil
+	}
+
+	return []interface{}{"trace_id", spanCtx.TraceID().String(), "span_id", spanCtx.SpanID().String()}
+}
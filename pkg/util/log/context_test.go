@@ -0,0 +1,69 @@
+package log
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestLoggerWithContextInjectsTraceCorrelation(t *testing.T) {
+	traceID, err := trace.TraceIDFromHex("0102030405060708090a0b0c0d0e0f10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	spanID, err := trace.SpanIDFromHex("0102030405060708")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	l := NewLogger(0, WithFormatter(LogfmtFormatter{}))
+
+	out := withKlogOutput(t, func() {
+		l.WithContext(ctx).Info("reconciled")
+	})
+
+	if want := "trace_id=" + traceID.String(); !strings.Contains(out, want) {
+		t.Fatalf("expected output to contain %q, got: %s", want, out)
+	}
+	if want := "span_id=" + spanID.String(); !strings.Contains(out, want) {
+		t.Fatalf("expected output to contain %q, got: %s", want, out)
+	}
+}
+
+func TestFromContextReturnsStoredLogger(t *testing.T) {
+	l := NewLogger(0, WithFormatter(LogfmtFormatter{}))
+	named := l.WithName("stored")
+
+	ctx := NewContext(context.Background(), named)
+
+	out := withKlogOutput(t, func() {
+		FromContext(ctx).Info("hello")
+	})
+	if want := "logger=stored"; !strings.Contains(out, want) {
+		t.Fatalf("expected output to contain %q, got: %s", want, out)
+	}
+}
+
+func TestFromContextWithoutStoredLoggerDiscards(t *testing.T) {
+	// logr.Discard() must not panic when used, and must not be our sink.
+	l := FromContext(context.Background())
+	if _, ok := l.GetSink().(*sink); ok {
+		t.Fatal("expected a discard logger, got our klog sink")
+	}
+
+	out := withKlogOutput(t, func() {
+		l.Info("should not appear")
+	})
+	if out != "" {
+		t.Fatalf("expected no output from the discard logger, got: %s", out)
+	}
+}
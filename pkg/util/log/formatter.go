@@ -0,0 +1,180 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// missingValue is substituted for the value of a trailing, unpaired key, the
+// same sentinel logr v1's own funcr formatter uses.
+const missingValue = "LOGR_MISSING_VALUE"
+
+// Formatter renders a log line's name chain, message and key/value pairs
+// into the string klog ultimately writes out.
+type Formatter interface {
+	// Format renders msg and kvs (an alternating list of keys and values,
+	// as passed to logr's Info/Error) into a single line. prefixes is the
+	// chain of names built up via WithName.
+	Format(prefixes []string, msg string, kvs []interface{}) string
+}
+
+// defaultFormatter picks a Formatter based on VCLUSTER_LOG_FORMAT, defaulting
+// to TextFormatter, the package's plain-text layout.
+func defaultFormatter() Formatter {
+	switch strings.ToLower(os.Getenv("VCLUSTER_LOG_FORMAT")) {
+	case "json":
+		return JSONFormatter{}
+	case "logfmt":
+		return LogfmtFormatter{}
+	default:
+		return TextFormatter{}
+	}
+}
+
+type kv struct {
+	key   string
+	value interface{}
+}
+
+// pairUp turns an alternating key/value list into key/value pairs, padding
+// a trailing unpaired key with missingValue.
+func pairUp(kvs []interface{}) []kv {
+	if len(kvs)%2 != 0 {
+		kvs = append(kvs, missingValue)
+	}
+
+	pairs := make([]kv, 0, len(kvs)/2)
+	for i := 0; i < len(kvs); i += 2 {
+		key, ok := kvs[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", kvs[i])
+		}
+		pairs = append(pairs, kv{key: key, value: kvs[i+1]})
+	}
+	return pairs
+}
+
+func formatLoose(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case error:
+		return t.Error()
+	default:
+		return fmt.Sprintf("%#v", v)
+	}
+}
+
+// TextFormatter renders lines in this package's plain-text layout: the name
+// chain and message joined with ": ", followed by a loose, space-separated
+// rendering of the remaining values. It is the default. Its output differs
+// from the package's earlier output in two ways: values added via WithValues
+// follow the message instead of joining the name chain, and a trailing
+// unpaired key is followed by LOGR_MISSING_VALUE.
+type TextFormatter struct{}
+
+func (TextFormatter) Format(prefixes []string, msg string, kvs []interface{}) string {
+	var extras []interface{}
+	for _, p := range pairUp(kvs) {
+		switch p.key {
+		case "level":
+			// the verbosity level was never surfaced in the old output
+		case "error":
+			extras = append(extras, p.value)
+		default:
+			extras = append(extras, p.key, p.value)
+		}
+	}
+
+	args := make([]string, 0, len(extras))
+	for _, e := range extras {
+		args = append(args, formatLoose(e))
+	}
+
+	ret := msg
+	if len(prefixes) > 0 {
+		ret = strings.Join(prefixes, ": ") + ": " + ret
+	}
+	if addString := strings.Join(args, " "); addString != "" {
+		ret += " " + addString
+	}
+	return ret
+}
+
+// LogfmtFormatter renders lines as logfmt: key=value pairs, with string
+// values quoted when they contain spaces or "=", errors rendered via
+// Error(), and anything else via "%+v".
+type LogfmtFormatter struct{}
+
+func (LogfmtFormatter) Format(prefixes []string, msg string, kvs []interface{}) string {
+	var b strings.Builder
+	writeLogfmtPair(&b, "msg", msg)
+	if len(prefixes) > 0 {
+		writeLogfmtPair(&b, "logger", strings.Join(prefixes, "."))
+	}
+	for _, p := range pairUp(kvs) {
+		writeLogfmtPair(&b, p.key, logfmtValue(p.value))
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func logfmtValue(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case error:
+		return t.Error()
+	case fmt.Stringer:
+		return t.String()
+	default:
+		return fmt.Sprintf("%+v", v)
+	}
+}
+
+func writeLogfmtPair(b *strings.Builder, key, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+	b.WriteString(key)
+	b.WriteByte('=')
+	if strings.ContainsAny(value, " =\"") {
+		b.WriteString(strconv.Quote(value))
+	} else {
+		b.WriteString(value)
+	}
+}
+
+// JSONFormatter renders lines as a single JSON object with "msg", "level",
+// "logger" (the joined name chain) and "error" pulled out as top-level
+// fields, plus whatever remaining key/value pairs were supplied.
+type JSONFormatter struct{}
+
+func (JSONFormatter) Format(prefixes []string, msg string, kvs []interface{}) string {
+	obj := make(map[string]interface{}, len(kvs)/2+3)
+	obj["msg"] = msg
+	if len(prefixes) > 0 {
+		obj["logger"] = strings.Join(prefixes, ".")
+	}
+
+	for _, p := range pairUp(kvs) {
+		switch p.key {
+		case "error":
+			if err, ok := p.value.(error); ok {
+				obj["error"] = err.Error()
+			} else if p.value != nil {
+				obj["error"] = fmt.Sprintf("%v", p.value)
+			}
+		default:
+			obj[p.key] = p.value
+		}
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Sprintf("%s (failed to marshal log line: %v)", msg, err)
+	}
+	return string(data)
+}
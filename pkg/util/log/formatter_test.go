@@ -0,0 +1,64 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestLogfmtFormatterFormat(t *testing.T) {
+	got := LogfmtFormatter{}.Format([]string{"controller", "sync"}, "reconciled", []interface{}{
+		"name", "my pod",
+		"error", errors.New("boom"),
+	})
+	want := `msg=reconciled logger=controller.sync name="my pod" error=boom`
+	if got != want {
+		t.Fatalf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestLogfmtFormatterMissingValue(t *testing.T) {
+	got := LogfmtFormatter{}.Format(nil, "oops", []interface{}{"dangling"})
+	want := `msg=oops dangling=` + missingValue
+	if got != want {
+		t.Fatalf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestJSONFormatterFormat(t *testing.T) {
+	out := JSONFormatter{}.Format([]string{"controller"}, "reconciled", []interface{}{
+		"level", 2,
+		"error", errors.New("boom"),
+		"name", "my-pod",
+	})
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &obj); err != nil {
+		t.Fatalf("Format() produced invalid JSON: %v, output: %s", err, out)
+	}
+
+	want := map[string]interface{}{
+		"msg":    "reconciled",
+		"logger": "controller",
+		"level":  float64(2),
+		"error":  "boom",
+		"name":   "my-pod",
+	}
+	for k, v := range want {
+		if obj[k] != v {
+			t.Errorf("field %q = %v, want %v (full output: %s)", k, obj[k], v, out)
+		}
+	}
+}
+
+func TestTextFormatterOmitsLevelAndErrorKey(t *testing.T) {
+	got := TextFormatter{}.Format([]string{"controller"}, "reconciled", []interface{}{
+		"level", 2,
+		"error", errors.New("boom"),
+		"name", "my-pod",
+	})
+	want := `controller: reconciled boom name my-pod`
+	if got != want {
+		t.Fatalf("Format() = %q, want %q", got, want)
+	}
+}
@@ -1,168 +1,163 @@
 package log
 
 import (
-	"fmt"
+	"context"
+	"sync/atomic"
+
 	"github.com/go-logr/logr"
 	"k8s.io/klog"
-	"strings"
 )
 
-type WithDepth interface {
-	WithDepth(depth int) logr.Logger
+// Option configures a Logger at construction time.
+type Option func(*sink)
+
+// WithFormatter overrides the Formatter used to render key/value pairs,
+// replacing whatever VCLUSTER_LOG_FORMAT would otherwise select.
+func WithFormatter(f Formatter) Option {
+	return func(s *sink) { s.formatter = f }
 }
 
-func NewLog(level int) logr.Logger {
-	return &log{
-		level: level,
-		depth: 1,
-	}
+// WithContext binds ctx to the root Logger so every line it logs carries
+// whatever trace/span correlation ctx holds. Most call sites should prefer
+// Logger.WithContext instead; this option is for loggers that are
+// constructed already bound to a context.
+func WithContext(ctx context.Context) Option {
+	return func(s *sink) { s.ctx = ctx }
 }
 
-type log struct {
-	current  int
-	level    int
-	prefixes []string
-	depth    int
+// Logger wraps a logr.Logger backed by klog with the ability to change the
+// verbosity threshold at runtime, without rebuilding the logger tree.
+type Logger struct {
+	logr.Logger
+
+	sink *sink
 }
 
-func (l *log) WithDepth(depth int) logr.Logger {
-	return &log{
-		level:    l.level,
-		current:  l.current,
-		prefixes: l.prefixes,
-		depth:    depth,
-	}
+// SetLevel atomically updates the verbosity threshold shared by l and every
+// Logger derived from it via V, WithName, WithValues, WithContext, etc.
+func (l Logger) SetLevel(level int) {
+	atomic.StoreInt32(l.sink.level, int32(level))
 }
 
-// Info logs a non-error message with the given key/value pairs as context.
-//
-// The msg argument should be used to add some constant description to
-// the log line.  The key/value pairs can then be used to add additional
-// variable information.  The key/value pairs should alternate string
-// keys and arbitrary values.
-func (l *log) Info(msg string, keysAndValues ...interface{}) {
-	klog.InfoDepth(l.depth, l.formatMsg(msg, keysAndValues...))
-}
-
-// Enabled tests whether this InfoLogger is enabled.  For example,
-// commandline flags might be used to set the logging verbosity and disable
-// some info logs.
-func (l *log) Enabled() bool {
-	return true
-}
-
-// Error logs an error, with the given message and key/value pairs as context.
-// It functions similarly to calling Info with the "error" named value, but may
-// have unique behavior, and should be preferred for logging errors (see the
-// package documentations for more information).
-//
-// The msg field should be used to add context to any underlying error,
-// while the err field should be used to attach the actual error that
-// triggered this log line, if present.
-func (l *log) Error(err error, msg string, keysAndValues ...interface{}) {
-	newKeysAndValues := []interface{}{err}
-	newKeysAndValues = append(newKeysAndValues, keysAndValues...)
-	klog.ErrorDepth(l.depth, l.formatMsg(msg, newKeysAndValues...))
-}
-
-// V returns an InfoLogger value for a specific verbosity level.  A higher
-// verbosity level means a log message is less important.  It's illegal to
-// pass a log level less than zero.
-func (l *log) V(level int) logr.Logger {
-	if level < l.level {
-		return &silent{}
-	}
+// Level returns the verbosity threshold currently in effect.
+func (l Logger) Level() int {
+	return int(atomic.LoadInt32(l.sink.level))
+}
 
-	prefixes := []string{}
-	prefixes = append(prefixes, l.prefixes...)
-	return &log{
-		level:    l.level,
-		current:  level,
-		prefixes: prefixes,
-		depth:    l.depth,
+// WithContext returns a logr.Logger that correlates every line it logs with
+// ctx, e.g. by attaching the trace_id/span_id of an active OpenTelemetry span.
+func (l Logger) WithContext(ctx context.Context) logr.Logger {
+	clone := l.sink.clone()
+	clone.ctx = ctx
+	return logr.New(clone)
+}
+
+// NewLogger creates a Logger that logs through klog at verbosities <= level,
+// returning a handle that also allows changing the threshold at runtime via
+// SetLevel.
+func NewLogger(level int, opts ...Option) Logger {
+	s := &sink{
+		level:     new(int32),
+		depth:     1,
+		formatter: defaultFormatter(),
 	}
+	atomic.StoreInt32(s.level, int32(level))
+	for _, opt := range opts {
+		opt(s)
+	}
+	return Logger{Logger: logr.New(s), sink: s}
 }
 
-// WithValues adds some key-value pairs of context to a logger.
-// See Info for documentation on how key/value pairs work.
-func (l *log) WithValues(keysAndValues ...interface{}) logr.Logger {
-	prefixes := []string{}
-	prefixes = append(prefixes, l.prefixes...)
-	prefixes = append(prefixes, formatKeysAndValues(keysAndValues...))
+// NewLog creates a logr.Logger that logs through klog at verbosities <= level.
+func NewLog(level int) logr.Logger {
+	return NewLogger(level).Logger
+}
 
-	return &log{
-		level:    l.level,
-		current:  l.current,
-		prefixes: prefixes,
-		depth:    l.depth,
-	}
+// sink implements logr.LogSink and logr.CallDepthLogSink, rendering lines
+// through klog using the configured Formatter.
+type sink struct {
+	level     *int32 // shared across every Logger derived from the same root, see Logger.SetLevel
+	name      []string
+	values    []interface{}
+	depth     int
+	formatter Formatter
+	ctx       context.Context
+	runtime   logr.RuntimeInfo
 }
 
-// WithName adds a new element to the logger's name.
-// Successive calls with WithName continue to append
-// suffixes to the logger's name.  It's strongly reccomended
-// that name segments contain only letters, digits, and hyphens
-// (see the package documentation for more information).
-func (l *log) WithName(name string) logr.Logger {
-	if name == "" {
-		return &log{
-			level:    l.level,
-			current:  l.current,
-			prefixes: l.prefixes,
-			depth:    l.depth,
-		}
+var (
+	_ logr.LogSink          = &sink{}
+	_ logr.CallDepthLogSink = &sink{}
+)
+
+func (s *sink) clone() *sink {
+	name := make([]string, len(s.name))
+	copy(name, s.name)
+	values := make([]interface{}, len(s.values))
+	copy(values, s.values)
+	return &sink{
+		level:     s.level,
+		name:      name,
+		values:    values,
+		depth:     s.depth,
+		formatter: s.formatter,
+		ctx:       s.ctx,
+		runtime:   s.runtime,
 	}
+}
 
-	prefixes := []string{}
-	prefixes = append(prefixes, l.prefixes...)
-	prefixes = append(prefixes, name)
+func (s *sink) Init(info logr.RuntimeInfo) {
+	s.runtime = info
+}
 
-	return &log{
-		level:    l.level,
-		current:  l.current,
-		prefixes: prefixes,
-		depth:    l.depth,
-	}
+// Enabled tests whether a log line at the given verbosity level would be
+// logged, honoring both our own configured threshold and klog's -v flag.
+func (s *sink) Enabled(level int) bool {
+	return int32(level) <= atomic.LoadInt32(s.level) && bool(klog.V(klog.Level(level)))
 }
 
-func (l *log) formatMsg(msg string, keysAndValues ...interface{}) string {
-	prefixes := strings.Join(l.prefixes, ": ")
-	addString := formatKeysAndValues(keysAndValues...)
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	kvs := append([]interface{}{"level", level}, keysAndValues...)
+	klog.InfoDepth(s.callDepth(), s.render(msg, kvs))
+}
 
-	retString := msg
-	if prefixes != "" {
-		retString = prefixes + ": " + retString
-	}
-	if addString != "" {
-		retString += " " + addString
-	}
-	// if l.current != 0 {
-	//	retString = "(" + strconv.Itoa(l.current) + ") " + retString
-	// }
-	return retString
-}
-
-func formatKeysAndValues(keysAndValues ...interface{}) string {
-	args := []string{}
-	for _, kv := range keysAndValues {
-		switch t := kv.(type) {
-		case string:
-			args = append(args, t)
-		case error:
-			args = append(args, t.Error())
-		default:
-			args = append(args, fmt.Sprintf("%#v", kv))
-		}
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	kvs := append([]interface{}{"error", err}, keysAndValues...)
+	klog.ErrorDepth(s.callDepth(), s.render(msg, kvs))
+}
+
+func (s *sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	clone := s.clone()
+	clone.values = append(clone.values, keysAndValues...)
+	return clone
+}
+
+func (s *sink) WithName(name string) logr.LogSink {
+	if name == "" {
+		return s.clone()
 	}
 
-	return strings.Join(args, " ")
+	clone := s.clone()
+	clone.name = append(clone.name, name)
+	return clone
 }
 
-type silent struct{}
+// WithCallDepth implements logr.CallDepthLogSink, adjusting the stack depth
+// klog uses to compute the file/line of a log line.
+func (s *sink) WithCallDepth(depth int) logr.LogSink {
+	clone := s.clone()
+	clone.depth += depth
+	return clone
+}
+
+func (s *sink) callDepth() int {
+	return s.depth + s.runtime.CallDepth
+}
 
-func (s *silent) Info(msg string, keysAndValues ...interface{})             {}
-func (s *silent) Enabled() bool                                             { return false }
-func (s *silent) Error(err error, msg string, keysAndValues ...interface{}) {}
-func (s *silent) V(level int) logr.Logger                                   { return s }
-func (s *silent) WithValues(keysAndValues ...interface{}) logr.Logger       { return s }
-func (s *silent) WithName(name string) logr.Logger                          { return s }
+func (s *sink) render(msg string, kvs []interface{}) string {
+	combined := make([]interface{}, 0, len(s.values)+len(kvs))
+	combined = append(combined, s.values...)
+	combined = append(combined, kvs...)
+	combined = append(combined, s.contextKeysAndValues()...)
+	return s.formatter.Format(s.name, msg, combined)
+}
@@ -0,0 +1,71 @@
+package log
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	"k8s.io/klog"
+)
+
+// TestMain raises klog's own -v threshold so these tests exercise only our
+// sink's Enabled logic, not klog's independent verbosity gate.
+func TestMain(m *testing.M) {
+	klog.InitFlags(nil)
+	_ = flag.Set("v", "10")
+	_ = flag.Set("logtostderr", "false")
+	os.Exit(m.Run())
+}
+
+func TestSinkEnabled(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		level     int
+		want      bool
+	}{
+		{name: "base level always enabled", threshold: 0, level: 0, want: true},
+		{name: "more verbose than threshold is disabled", threshold: 0, level: 5, want: false},
+		{name: "less verbose than threshold is enabled", threshold: 5, level: 0, want: true},
+		{name: "at threshold is enabled", threshold: 5, level: 5, want: true},
+		{name: "above threshold is disabled", threshold: 5, level: 10, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLogger(tt.threshold)
+			if got := l.sink.Enabled(tt.level); got != tt.want {
+				t.Errorf("Enabled(%d) with threshold %d = %v, want %v", tt.level, tt.threshold, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoggerSetLevelRaisesThreshold(t *testing.T) {
+	l := NewLogger(0)
+	if !l.sink.Enabled(0) {
+		t.Fatal("V(0) should be enabled at threshold 0")
+	}
+	if l.sink.Enabled(3) {
+		t.Fatal("V(3) should be disabled at threshold 0")
+	}
+
+	l.SetLevel(3)
+	if !l.sink.Enabled(0) {
+		t.Fatal("V(0) should still be enabled after raising the threshold")
+	}
+	if !l.sink.Enabled(3) {
+		t.Fatal("V(3) should be enabled once the threshold is raised to 3")
+	}
+}
+
+func withKlogOutput(t *testing.T, fn func()) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	klog.SetOutput(&buf)
+	fn()
+	klog.Flush()
+	return buf.String()
+}
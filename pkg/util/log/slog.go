@@ -0,0 +1,122 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+)
+
+// slogCallDepth accounts for the two frames slog.Logger adds between a call
+// site and Handler.Handle (Logger.Info/Error -> Logger.log -> Handler.Handle),
+// so klog still reports accurate file/line.
+const slogCallDepth = 2
+
+// NewSlogHandler returns a slog.Handler that logs through the same klog
+// backend and Formatter as NewLog, so code written against log/slog gets the
+// same formatting and -v gating as existing logr call sites.
+func NewSlogHandler(level int) slog.Handler {
+	return NewLogger(level).SlogHandler()
+}
+
+// SlogHandler returns a slog.Handler backed by the same sink as l, sharing
+// its verbosity threshold, Formatter and name/value state.
+func (l Logger) SlogHandler() slog.Handler {
+	clone, _ := l.sink.WithCallDepth(slogCallDepth).(*sink)
+	return &slogHandler{sink: clone}
+}
+
+// slogHandler adapts our logr.LogSink to slog.Handler.
+type slogHandler struct {
+	sink *sink
+
+	// groupPrefix is the "."-joined chain of names opened via WithGroup. Per
+	// the slog.Handler contract, it qualifies the keys of every attribute
+	// added after it, whether passed to WithAttrs or to a Handle call.
+	groupPrefix string
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.sink.Enabled(slogLevelToV(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	kvs := make([]interface{}, 0, r.NumAttrs()*2)
+	r.Attrs(func(a slog.Attr) bool {
+		kvs = append(kvs, flattenAttr(h.groupPrefix, a)...)
+		return true
+	})
+
+	if r.Level >= slog.LevelError {
+		h.sink.Error(extractError(kvs), r.Message, kvs...)
+		return nil
+	}
+
+	h.sink.Info(slogLevelToV(r.Level), r.Message, kvs...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	kvs := make([]interface{}, 0, len(attrs)*2)
+	for _, a := range attrs {
+		kvs = append(kvs, flattenAttr(h.groupPrefix, a)...)
+	}
+
+	clone, _ := h.sink.WithValues(kvs...).(*sink)
+	return &slogHandler{sink: clone, groupPrefix: h.groupPrefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	prefix := name
+	if h.groupPrefix != "" {
+		prefix = h.groupPrefix + "." + name
+	}
+	return &slogHandler{sink: h.sink, groupPrefix: prefix}
+}
+
+// flattenAttr expands a (possibly nested) slog.Attr group into an
+// alternating key/value list, dot-joining group names into their keys.
+// It resolves a.Value first so slog.LogValuer attributes (e.g. a type that
+// redacts itself via LogValue) are logged as their resolved value, not the
+// raw one, per the Handler.Handle contract.
+func flattenAttr(prefix string, a slog.Attr) []interface{} {
+	a.Value = a.Value.Resolve()
+
+	key := a.Key
+	if prefix != "" {
+		key = prefix + "." + key
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		var out []interface{}
+		for _, ga := range a.Value.Group() {
+			out = append(out, flattenAttr(key, ga)...)
+		}
+		return out
+	}
+
+	return []interface{}{key, a.Value.Any()}
+}
+
+// extractError looks for an "err" or "error" attribute holding an error, the
+// convention most slog call sites use to report a failure.
+func extractError(kvs []interface{}) error {
+	for i := 0; i+1 < len(kvs); i += 2 {
+		key, ok := kvs[i].(string)
+		if !ok || (key != "err" && key != "error") {
+			continue
+		}
+		if err, ok := kvs[i+1].(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// slogLevelToV maps a slog.Level onto our klog V-level scale: slog's Info
+// and above map to V(0), while Debug and lower levels become increasingly
+// verbose V-levels.
+func slogLevelToV(level slog.Level) int {
+	if level >= slog.LevelInfo {
+		return 0
+	}
+	return int((slog.LevelInfo - level) / 4)
+}
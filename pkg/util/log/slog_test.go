@@ -0,0 +1,75 @@
+package log
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandlerPreservesCallerDepth(t *testing.T) {
+	out := withKlogOutput(t, func() {
+		handler := NewLogger(0).SlogHandler()
+		slog.New(handler).Info("hello from slog")
+	})
+
+	if strings.Contains(out, "slog.go") {
+		t.Fatalf("expected the log line to report this test file, not the slog bridge itself, got: %s", out)
+	}
+	if !strings.Contains(out, "slog_test.go") {
+		t.Fatalf("expected the log line to report slog_test.go as the caller, got: %s", out)
+	}
+}
+
+func TestSlogHandlerWithGroupQualifiesKeys(t *testing.T) {
+	l := NewLogger(0, WithFormatter(LogfmtFormatter{}))
+
+	out := withKlogOutput(t, func() {
+		slog.New(l.SlogHandler()).WithGroup("request").Info("done", "status", 200)
+	})
+	if !strings.Contains(out, "request.status=200") {
+		t.Fatalf("expected WithGroup to qualify the status key as request.status, got: %s", out)
+	}
+
+	// slog.Group in-call is documented to be equivalent to WithGroup followed
+	// by the grouped attres; both must qualify the key the same way.
+	inCallOut := withKlogOutput(t, func() {
+		slog.New(l.SlogHandler()).Info("done", slog.Group("request", slog.Int("status", 200)))
+	})
+	if !strings.Contains(inCallOut, "request.status=200") {
+		t.Fatalf("expected slog.Group to qualify the status key as request.status, got: %s", inCallOut)
+	}
+}
+
+func TestSlogHandlerWithGroupQualifiesWithAttrs(t *testing.T) {
+	l := NewLogger(0, WithFormatter(LogfmtFormatter{}))
+
+	out := withKlogOutput(t, func() {
+		slog.New(l.SlogHandler()).WithGroup("request").With("status", 200).Info("done")
+	})
+	if !strings.Contains(out, "request.status=200") {
+		t.Fatalf("expected a group opened before WithAttrs to qualify its keys, got: %s", out)
+	}
+}
+
+type redactedValue struct {
+	Value string
+}
+
+func (r redactedValue) LogValue() slog.Value {
+	return slog.StringValue("REDACTED")
+}
+
+func TestSlogHandlerResolvesLogValuer(t *testing.T) {
+	l := NewLogger(0, WithFormatter(LogfmtFormatter{}))
+
+	out := withKlogOutput(t, func() {
+		slog.New(l.SlogHandler()).Info("login", "password", redactedValue{Value: "hunter2"})
+	})
+
+	if strings.Contains(out, "hunter2") {
+		t.Fatalf("expected the LogValuer's redacted value to be logged, but the secret leaked: %s", out)
+	}
+	if !strings.Contains(out, "password=REDACTED") {
+		t.Fatalf("expected password=REDACTED in output, got: %s", out)
+	}
+}